@@ -97,6 +97,7 @@ func (c *Client) Connect(ctx context.Context) error {
 
 	if err := pool.Ping(ctxWithTimeout); err != nil {
 		c.logger.Error().WithError(err).Msg("failed to ping database")
+		pool.Close()
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
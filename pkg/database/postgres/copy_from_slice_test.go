@@ -0,0 +1,44 @@
+package postgres
+
+import "testing"
+
+func TestCopyFromSlice(t *testing.T) {
+	type user struct {
+		Name  string
+		Email string
+	}
+
+	users := []user{
+		{Name: "Ada", Email: "ada@example.com"},
+		{Name: "Alan", Email: "alan@example.com"},
+	}
+
+	src := CopyFromSlice(users, func(u user) ([]any, error) {
+		return []any{u.Name, u.Email}, nil
+	})
+
+	var got []user
+	for src.Next() {
+		values, err := src.Values()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, user{Name: values[0].(string), Email: values[1].(string)})
+	}
+	if err := src.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != len(users) {
+		t.Fatalf("got %d rows, want %d", len(got), len(users))
+	}
+	for i, u := range users {
+		if got[i] != u {
+			t.Errorf("row %d = %+v, want %+v", i, got[i], u)
+		}
+	}
+
+	if src.Next() {
+		t.Error("Next() returned true after exhausting the source")
+	}
+}
@@ -0,0 +1,435 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// OperationKind identifies one additive DDL step inside an ExpandMigration.
+type OperationKind string
+
+const (
+	OpAddColumn    OperationKind = "add_column"
+	OpRenameColumn OperationKind = "rename_column"
+	OpCreateTable  OperationKind = "create_table"
+	OpRawSQL       OperationKind = "raw_sql"
+)
+
+// Operation is a single additive DDL step. Only the fields relevant to Kind
+// need to be set: add_column uses Table, Column, ColumnType and Default;
+// rename_column uses Table, Column, NewColumn and ColumnType; create_table
+// uses Table and SQL; raw_sql uses SQL.
+type Operation struct {
+	Kind       OperationKind `json:"kind"`
+	Table      string        `json:"table,omitempty"`
+	Column     string        `json:"column,omitempty"`
+	NewColumn  string        `json:"new_column,omitempty"`
+	ColumnType string        `json:"column_type,omitempty"`
+	Default    string        `json:"default,omitempty"`
+	SQL        string        `json:"sql,omitempty"`
+}
+
+// ExpandMigration is the JSON/DSL definition of one expand/contract cycle:
+// a named, additive change to BaseSchema that Start applies while the
+// previous version keeps reading and writing the base tables and the new
+// version is exposed through a versioned schema of updatable views.
+type ExpandMigration struct {
+	Name       string      `json:"name"`
+	ParentName string      `json:"parent_name,omitempty"`
+	BaseSchema string      `json:"base_schema,omitempty"`
+	Operations []Operation `json:"operations"`
+}
+
+func (em ExpandMigration) baseSchema() string {
+	if em.BaseSchema == "" {
+		return "public"
+	}
+	return em.BaseSchema
+}
+
+func (em ExpandMigration) versionedSchema() string {
+	return fmt.Sprintf("%s_%s", em.baseSchema(), em.Name)
+}
+
+func syncTriggerName(table, column string) string {
+	return fmt.Sprintf("easy_pg_sync_%s_%s", table, column)
+}
+
+// quoteIdent sanitizes one or more identifier parts (schema, table, column,
+// ...) into a safely quoted, dot-separated SQL identifier, so values coming
+// from an ExpandMigration's JSON/DSL definition can't be used to smuggle
+// arbitrary SQL into generated DDL.
+func quoteIdent(parts ...string) string {
+	return pgx.Identifier(parts).Sanitize()
+}
+
+// ExpandContractManager implements zero-downtime schema evolution using the
+// expand/contract pattern: Start expands the schema additively and installs
+// triggers so both the old and new application versions can read and write
+// concurrently, Complete contracts it once every consumer has cut over, and
+// Rollback undoes an expansion that didn't work out.
+type ExpandContractManager struct {
+	client *Client
+}
+
+// ExpandContract returns the zero-downtime migration subsystem for m.
+func (m *MigrationManager) ExpandContract() *ExpandContractManager {
+	return &ExpandContractManager{client: m.client}
+}
+
+func (e *ExpandContractManager) initSchema(ctx context.Context) error {
+	_, err := e.client.Exec(ctx, `
+		CREATE SCHEMA IF NOT EXISTS easy_pg;
+
+		CREATE TABLE IF NOT EXISTS easy_pg.schema_migrations (
+			name         TEXT PRIMARY KEY,
+			parent_name  TEXT REFERENCES easy_pg.schema_migrations(name),
+			base_schema  TEXT NOT NULL,
+			definition   JSONB NOT NULL,
+			status       TEXT NOT NULL CHECK (status IN ('active', 'completed')),
+			created_at   TIMESTAMP NOT NULL DEFAULT now(),
+			completed_at TIMESTAMP,
+			UNIQUE (parent_name)
+		);
+
+		CREATE UNIQUE INDEX IF NOT EXISTS easy_pg_one_active_migration
+			ON easy_pg.schema_migrations ((status))
+			WHERE status = 'active';
+	`)
+	if err != nil {
+		return fmt.Errorf("error initializing easy_pg schema: %w", err)
+	}
+	return nil
+}
+
+// LatestVersion returns the name of the most recently started migration
+// (active or completed), or "" if none has ever run.
+func (e *ExpandContractManager) LatestVersion(ctx context.Context) (string, error) {
+	if err := e.initSchema(ctx); err != nil {
+		return "", err
+	}
+
+	var name string
+	err := e.client.QueryRow(ctx, `
+		SELECT name FROM easy_pg.schema_migrations
+		WHERE status IN ('active', 'completed')
+		ORDER BY created_at DESC
+		LIMIT 1
+	`).Scan(&name)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting latest expand/contract version: %w", err)
+	}
+	return name, nil
+}
+
+// IsActiveMigrationPeriod reports whether a migration is currently expanded
+// (Start'd but not yet Complete'd or Rollback'd).
+func (e *ExpandContractManager) IsActiveMigrationPeriod(ctx context.Context) (bool, error) {
+	if err := e.initSchema(ctx); err != nil {
+		return false, err
+	}
+
+	var active bool
+	err := e.client.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM easy_pg.schema_migrations WHERE status = 'active')
+	`).Scan(&active)
+	if err != nil {
+		return false, fmt.Errorf("error checking active migration period: %w", err)
+	}
+	return active, nil
+}
+
+// Start expands the schema: it records the migration, applies the additive
+// DDL described by migration.Operations to the base tables, and creates a
+// versioned schema of updatable views plus sync triggers so that code
+// written against the old shape and code written against the new shape can
+// both run against the database at the same time.
+func (e *ExpandContractManager) Start(ctx context.Context, migration ExpandMigration) error {
+	if migration.Name == "" {
+		return fmt.Errorf("expand migration must have a name")
+	}
+
+	if err := e.initSchema(ctx); err != nil {
+		return err
+	}
+
+	latest, err := e.LatestVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if migration.ParentName != latest {
+		return fmt.Errorf("expand migration %q has parent %q but latest version is %q", migration.Name, migration.ParentName, latest)
+	}
+
+	active, err := e.IsActiveMigrationPeriod(ctx)
+	if err != nil {
+		return err
+	}
+	if active {
+		return fmt.Errorf("cannot start %q: a migration is already active", migration.Name)
+	}
+
+	definition, err := json.Marshal(migration)
+	if err != nil {
+		return fmt.Errorf("error marshaling migration %q: %w", migration.Name, err)
+	}
+
+	return e.client.ExecTx(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO easy_pg.schema_migrations (name, parent_name, base_schema, definition, status)
+			VALUES ($1, NULLIF($2, ''), $3, $4, 'active')
+		`, migration.Name, migration.ParentName, migration.baseSchema(), definition)
+		if err != nil {
+			return fmt.Errorf("error recording migration %q: %w", migration.Name, err)
+		}
+
+		versionedSchema := migration.versionedSchema()
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`CREATE SCHEMA %s`, quoteIdent(versionedSchema))); err != nil {
+			return fmt.Errorf("error creating schema %s: %w", versionedSchema, err)
+		}
+
+		touchedTables := map[string]bool{}
+		for _, op := range migration.Operations {
+			if err := e.applyExpandOperation(ctx, tx, migration, op); err != nil {
+				return err
+			}
+			if op.Table != "" {
+				touchedTables[op.Table] = true
+			}
+		}
+
+		for table := range touchedTables {
+			if err := e.createVersionedView(ctx, tx, migration, table); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (e *ExpandContractManager) applyExpandOperation(ctx context.Context, tx pgx.Tx, migration ExpandMigration, op Operation) error {
+	baseSchema := migration.baseSchema()
+
+	switch op.Kind {
+	case OpAddColumn:
+		def := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`,
+			quoteIdent(baseSchema, op.Table), quoteIdent(op.Column), op.ColumnType)
+		if op.Default != "" {
+			def += fmt.Sprintf(` DEFAULT %s`, op.Default)
+		}
+		if _, err := tx.Exec(ctx, def); err != nil {
+			return fmt.Errorf("error adding column %s.%s: %w", op.Table, op.Column, err)
+		}
+		return nil
+
+	case OpRenameColumn:
+		table := quoteIdent(baseSchema, op.Table)
+		oldColumn := quoteIdent(op.Column)
+		newColumn := quoteIdent(op.NewColumn)
+
+		if _, err := tx.Exec(ctx, fmt.Sprintf(
+			`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`,
+			table, newColumn, op.ColumnType,
+		)); err != nil {
+			return fmt.Errorf("error adding column %s.%s: %w", op.Table, op.NewColumn, err)
+		}
+
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`UPDATE %s SET %s = %s`, table, newColumn, oldColumn)); err != nil {
+			return fmt.Errorf("error backfilling %s.%s: %w", op.Table, op.NewColumn, err)
+		}
+
+		trigger := quoteIdent(syncTriggerName(op.Table, op.NewColumn))
+		triggerFunc := quoteIdent(baseSchema, syncTriggerName(op.Table, op.NewColumn))
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`
+			CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $trigger$
+			BEGIN
+				IF NEW.%s IS DISTINCT FROM OLD.%s AND NEW.%s IS NOT DISTINCT FROM OLD.%s THEN
+					NEW.%s := NEW.%s;
+				ELSE
+					NEW.%s := NEW.%s;
+				END IF;
+				RETURN NEW;
+			END;
+			$trigger$ LANGUAGE plpgsql;
+
+			DROP TRIGGER IF EXISTS %s ON %s;
+			CREATE TRIGGER %s
+				BEFORE INSERT OR UPDATE ON %s
+				FOR EACH ROW EXECUTE FUNCTION %s();
+		`,
+			triggerFunc,
+			oldColumn, oldColumn, newColumn, newColumn,
+			newColumn, oldColumn,
+			oldColumn, newColumn,
+			trigger, table,
+			trigger,
+			table,
+			triggerFunc,
+		)); err != nil {
+			return fmt.Errorf("error installing sync trigger for %s.%s: %w", op.Table, op.NewColumn, err)
+		}
+		return nil
+
+	case OpCreateTable:
+		if _, err := tx.Exec(ctx, op.SQL); err != nil {
+			return fmt.Errorf("error creating table %s: %w", op.Table, err)
+		}
+		return nil
+
+	case OpRawSQL:
+		if _, err := tx.Exec(ctx, op.SQL); err != nil {
+			return fmt.Errorf("error applying raw_sql operation: %w", err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown operation kind %q", op.Kind)
+	}
+}
+
+func (e *ExpandContractManager) createVersionedView(ctx context.Context, tx pgx.Tx, migration ExpandMigration, table string) error {
+	_, err := tx.Exec(ctx, fmt.Sprintf(
+		`CREATE OR REPLACE VIEW %s AS SELECT * FROM %s`,
+		quoteIdent(migration.versionedSchema(), table), quoteIdent(migration.baseSchema(), table),
+	))
+	if err != nil {
+		return fmt.Errorf("error creating versioned view %s.%s: %w", migration.versionedSchema(), table, err)
+	}
+	return nil
+}
+
+// Complete contracts the schema: it drops the versioned views (no longer
+// needed now that the base tables themselves hold the final shape), drops
+// the columns and sync triggers the old version relied on, and marks the
+// migration completed.
+func (e *ExpandContractManager) Complete(ctx context.Context, name string) error {
+	migration, err := e.loadMigration(ctx, name, "active")
+	if err != nil {
+		return err
+	}
+
+	return e.client.ExecTx(ctx, func(tx pgx.Tx) error {
+		baseSchema := migration.baseSchema()
+
+		for _, op := range migration.Operations {
+			if op.Kind != OpRenameColumn {
+				continue
+			}
+
+			table := quoteIdent(baseSchema, op.Table)
+			trigger := quoteIdent(syncTriggerName(op.Table, op.NewColumn))
+			triggerFunc := quoteIdent(baseSchema, syncTriggerName(op.Table, op.NewColumn))
+
+			if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP TRIGGER IF EXISTS %s ON %s`, trigger, table)); err != nil {
+				return fmt.Errorf("error dropping sync trigger for %s.%s: %w", op.Table, op.NewColumn, err)
+			}
+			if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP FUNCTION IF EXISTS %s()`, triggerFunc)); err != nil {
+				return fmt.Errorf("error dropping sync function for %s.%s: %w", op.Table, op.NewColumn, err)
+			}
+			if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN IF EXISTS %s`, table, quoteIdent(op.Column))); err != nil {
+				return fmt.Errorf("error dropping old column %s.%s: %w", op.Table, op.Column, err)
+			}
+		}
+
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP SCHEMA IF EXISTS %s CASCADE`, quoteIdent(migration.versionedSchema()))); err != nil {
+			return fmt.Errorf("error dropping schema %s: %w", migration.versionedSchema(), err)
+		}
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE easy_pg.schema_migrations SET status = 'completed', completed_at = now() WHERE name = $1
+		`, name); err != nil {
+			return fmt.Errorf("error marking migration %q completed: %w", name, err)
+		}
+
+		return nil
+	})
+}
+
+// Rollback reverts an active expansion: it drops the versioned schema,
+// reverts the additive DDL applied by Start, and deletes the migration's
+// row so its name and parent_name slots are free again - the chain behaves
+// as if the migration never started, and the name can be re-Start'd or
+// superseded by a different child of the same parent.
+func (e *ExpandContractManager) Rollback(ctx context.Context, name string) error {
+	migration, err := e.loadMigration(ctx, name, "active")
+	if err != nil {
+		return err
+	}
+
+	return e.client.ExecTx(ctx, func(tx pgx.Tx) error {
+		baseSchema := migration.baseSchema()
+
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP SCHEMA IF EXISTS %s CASCADE`, quoteIdent(migration.versionedSchema()))); err != nil {
+			return fmt.Errorf("error dropping schema %s: %w", migration.versionedSchema(), err)
+		}
+
+		for _, op := range migration.Operations {
+			table := quoteIdent(baseSchema, op.Table)
+
+			switch op.Kind {
+			case OpAddColumn:
+				if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN IF EXISTS %s`, table, quoteIdent(op.Column))); err != nil {
+					return fmt.Errorf("error reverting column %s.%s: %w", op.Table, op.Column, err)
+				}
+
+			case OpRenameColumn:
+				trigger := quoteIdent(syncTriggerName(op.Table, op.NewColumn))
+				triggerFunc := quoteIdent(baseSchema, syncTriggerName(op.Table, op.NewColumn))
+
+				if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP TRIGGER IF EXISTS %s ON %s`, trigger, table)); err != nil {
+					return fmt.Errorf("error reverting sync trigger for %s.%s: %w", op.Table, op.NewColumn, err)
+				}
+				if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP FUNCTION IF EXISTS %s()`, triggerFunc)); err != nil {
+					return fmt.Errorf("error reverting sync function for %s.%s: %w", op.Table, op.NewColumn, err)
+				}
+				if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN IF EXISTS %s`, table, quoteIdent(op.NewColumn))); err != nil {
+					return fmt.Errorf("error reverting column %s.%s: %w", op.Table, op.NewColumn, err)
+				}
+
+			case OpCreateTable:
+				if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s CASCADE`, table)); err != nil {
+					return fmt.Errorf("error reverting table %s: %w", op.Table, err)
+				}
+
+			case OpRawSQL:
+				// raw_sql operations are an escape hatch; reverting them is the
+				// migration author's responsibility and out of scope here.
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM easy_pg.schema_migrations WHERE name = $1
+		`, name); err != nil {
+			return fmt.Errorf("error removing rolled back migration %q: %w", name, err)
+		}
+
+		return nil
+	})
+}
+
+func (e *ExpandContractManager) loadMigration(ctx context.Context, name, wantStatus string) (ExpandMigration, error) {
+	var definition []byte
+	var status string
+	err := e.client.QueryRow(ctx, `
+		SELECT definition, status FROM easy_pg.schema_migrations WHERE name = $1
+	`, name).Scan(&definition, &status)
+	if err != nil {
+		return ExpandMigration{}, fmt.Errorf("error loading migration %q: %w", name, err)
+	}
+	if status != wantStatus {
+		return ExpandMigration{}, fmt.Errorf("migration %q is %s, not %s", name, status, wantStatus)
+	}
+
+	var migration ExpandMigration
+	if err := json.Unmarshal(definition, &migration); err != nil {
+		return ExpandMigration{}, fmt.Errorf("error unmarshaling migration %q: %w", name, err)
+	}
+	return migration, nil
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MigrationSource supplies the migrations Migrate, Rollback, and Status
+// operate on. The builtin implementations are DirSource (SQL files on
+// disk), FSSource (SQL files in any fs.FS, e.g. an embed.FS compiled into
+// the binary), and GoSource (migrations defined as Go code).
+type MigrationSource interface {
+	List() ([]Migration, error)
+}
+
+// DirSource loads migrations from SQL files in a directory on disk, using
+// the naming conventions documented on LoadMigrationsFromPath.
+type DirSource struct {
+	Path string
+}
+
+func (s DirSource) List() ([]Migration, error) {
+	migrations, err := loadMigrationsFromFS(os.DirFS(s.Path), ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations directory: %w", err)
+	}
+	return migrations, nil
+}
+
+// FSSource loads migrations from SQL files under Root in FS, using the same
+// naming conventions as DirSource. This is the way to ship migrations
+// embedded in a compiled binary via //go:embed.
+type FSSource struct {
+	FS   fs.FS
+	Root string
+}
+
+func (s FSSource) List() ([]Migration, error) {
+	root := s.Root
+	if root == "" {
+		root = "."
+	}
+
+	migrations, err := loadMigrationsFromFS(s.FS, root)
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations from fs.FS: %w", err)
+	}
+	return migrations, nil
+}
+
+// GoSource returns a fixed, programmatically-built list of migrations, such
+// as ones using Migration.GoUp/GoDown to run Go code that SQL can't express.
+type GoSource struct {
+	Migrations []Migration
+}
+
+func (s GoSource) List() ([]Migration, error) {
+	return s.Migrations, nil
+}
+
+// loadMigrationsFromFS implements the shared directory-walking logic behind
+// DirSource and FSSource.
+func loadMigrationsFromFS(fsys fs.FS, root string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*Migration)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		direction := ""
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		switch {
+		case strings.HasSuffix(base, ".up"):
+			direction = "up"
+			base = strings.TrimSuffix(base, ".up")
+		case strings.HasSuffix(base, ".down"):
+			direction = "down"
+			base = strings.TrimSuffix(base, ".down")
+		}
+
+		parts := strings.SplitN(base, "_", 2)
+		if len(parts) < 2 {
+			continue
+		}
+
+		version, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(root, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration file %s: %w", entry.Name(), err)
+		}
+
+		migration, exists := byKey[base]
+		if !exists {
+			migration = &Migration{
+				Version:     version,
+				Description: base,
+				Timestamp:   time.Now(),
+			}
+			byKey[base] = migration
+		}
+
+		switch direction {
+		case "up":
+			migration.UpSQL = string(content)
+			migration.SQL = migration.UpSQL
+		case "down":
+			migration.DownSQL = string(content)
+		default:
+			migration.SQL = string(content)
+			migration.UpSQL = migration.SQL
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byKey))
+	for _, migration := range byKey {
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
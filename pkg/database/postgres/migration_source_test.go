@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestFSSource(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INT);")},
+		"migrations/001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	}
+
+	migrations, err := FSSource{FS: fsys, Root: "migrations"}.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(migrations) != 1 {
+		t.Fatalf("got %d migrations, want 1", len(migrations))
+	}
+	if migrations[0].UpSQL != "CREATE TABLE users (id INT);" {
+		t.Errorf("UpSQL = %q", migrations[0].UpSQL)
+	}
+	if migrations[0].DownSQL != "DROP TABLE users;" {
+		t.Errorf("DownSQL = %q", migrations[0].DownSQL)
+	}
+}
+
+func TestGoSource(t *testing.T) {
+	want := []Migration{{Version: 1, Description: "seed"}}
+	migrations, err := GoSource{Migrations: want}.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(migrations) != 1 || migrations[0].Version != 1 {
+		t.Fatalf("got %+v, want %+v", migrations, want)
+	}
+}
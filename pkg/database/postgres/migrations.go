@@ -3,10 +3,7 @@ package postgres
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -16,28 +13,104 @@ import (
 type Migration struct {
 	Version     int
 	Description string
-	SQL         string
-	Timestamp   time.Time
+	// SQL holds the forward migration statement(s) and is kept for backward
+	// compatibility; migrations loaded from files set it equal to UpSQL, and
+	// ApplyMigration runs it only when UpSQL is empty. Prefer UpSQL in new
+	// code.
+	SQL     string
+	UpSQL   string
+	DownSQL string
+	// GoUp and GoDown let a migration be defined as Go code instead of SQL,
+	// for data transforms SQL can't express (see GoSource). When set, they
+	// take precedence over UpSQL/DownSQL and run inside the same
+	// transaction as the migrations-table bookkeeping, so both commit
+	// atomically.
+	GoUp      func(ctx context.Context, tx pgx.Tx) error
+	GoDown    func(ctx context.Context, tx pgx.Tx) error
+	Timestamp time.Time
+}
+
+// MigrationStatus reports the state of a single migration version, the way
+// `migrate version`/`migrate status` would for a golang-migrate project.
+type MigrationStatus struct {
+	Version     int
+	Description string
+	Applied     bool
+	AppliedAt   *time.Time
+	// Dirty is always false today: ApplyMigration and rollbackMigration run
+	// each migration inside a single transaction, so a crash mid-migration
+	// leaves the version pending rather than half-applied. The field exists
+	// for parity with tools that don't make that guarantee.
+	Dirty bool
+}
+
+// defaultMultiStatementMaxSize mirrors golang-migrate's pgx driver default of
+// 10MB for a single statement inside a multi-statement migration file.
+const defaultMultiStatementMaxSize = 10 * 1024 * 1024
+
+const defaultMigrationsTable = "migrations"
+
+// MigrationOptions configures how MigrationManager parses and applies SQL
+// migration files. The zero value is a ready-to-use, backwards-compatible
+// configuration: multi-statement files are executed as a single statement,
+// no per-statement timeout is applied, and the migrations table is named
+// "migrations".
+type MigrationOptions struct {
+	// MultiStatementEnabled splits each migration file into individual
+	// statements before executing them, instead of sending the whole file
+	// to the server as one statement.
+	MultiStatementEnabled bool
+	// MultiStatementMaxSize caps the size in bytes of any single statement
+	// once a file has been split. Defaults to 10MB when MultiStatementEnabled
+	// is set and this is left at 0.
+	MultiStatementMaxSize int
+	// StatementTimeoutMs sets a Postgres statement_timeout, in milliseconds,
+	// applied to each statement via SET LOCAL. 0 means no timeout.
+	StatementTimeoutMs int
+	// MigrationsTable overrides the name of the table used to track applied
+	// migrations. Defaults to "migrations".
+	MigrationsTable string
+}
+
+func (o MigrationOptions) withDefaults() MigrationOptions {
+	if o.MigrationsTable == "" {
+		o.MigrationsTable = defaultMigrationsTable
+	}
+	if o.MultiStatementEnabled && o.MultiStatementMaxSize == 0 {
+		o.MultiStatementMaxSize = defaultMultiStatementMaxSize
+	}
+	return o
 }
 
 type MigrationManager struct {
 	client *Client
+	opts   MigrationOptions
 }
 
-func NewMigrationManager(client *Client) *MigrationManager {
+// NewMigrationManager builds a MigrationManager for client. An optional
+// MigrationOptions can be passed to enable multi-statement files, a
+// statement timeout, or a custom migrations table name; omitting it keeps
+// the previous single-statement behavior.
+func NewMigrationManager(client *Client, opts ...MigrationOptions) *MigrationManager {
+	var o MigrationOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
 	return &MigrationManager{
 		client: client,
+		opts:   o.withDefaults(),
 	}
 }
 
 func (m *MigrationManager) InitMigrationTable(ctx context.Context) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS migrations (
+	query := fmt.Sprintf(`
+	CREATE TABLE IF NOT EXISTS %s (
 		id SERIAL PRIMARY KEY,
 		version INT NOT NULL UNIQUE,
 		description TEXT NOT NULL,
 		applied_at TIMESTAMP NOT NULL DEFAULT NOW()
-	);`
+	);`, m.opts.MigrationsTable)
 
 	_, err := m.client.Exec(ctx, query)
 	if err != nil {
@@ -46,46 +119,69 @@ func (m *MigrationManager) InitMigrationTable(ctx context.Context) error {
 	return nil
 }
 
+type appliedMigration struct {
+	version   int
+	appliedAt time.Time
+}
+
 func (m *MigrationManager) GetAppliedMigrations(ctx context.Context) (map[int]time.Time, error) {
-	query := `
+	query := fmt.Sprintf(`
 	SELECT version, applied_at
-	FROM migrations
-	ORDER BY version;`
+	FROM %s
+	ORDER BY version;`, m.opts.MigrationsTable)
 
-	rows, err := m.client.QueryRows(ctx, query)
+	applied, err := CollectRows(ctx, m.client, query, nil, func(row pgx.Row) (appliedMigration, error) {
+		var am appliedMigration
+		err := row.Scan(&am.version, &am.appliedAt)
+		return am, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error querying migrations: %w", err)
 	}
-	defer rows.Close()
 
-	result := make(map[int]time.Time)
-	for rows.Next() {
-		var version int
-		var appliedAt time.Time
-		if err := rows.Scan(&version, &appliedAt); err != nil {
-			return nil, fmt.Errorf("error scanning migration: %w", err)
-		}
-		result[version] = appliedAt
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating migrations: %w", err)
+	result := make(map[int]time.Time, len(applied))
+	for _, am := range applied {
+		result[am.version] = am.appliedAt
 	}
 
 	return result, nil
 }
 
 func (m *MigrationManager) ApplyMigration(ctx context.Context, migration Migration) error {
+	var statements []string
+	if migration.GoUp == nil {
+		upSQL := migration.UpSQL
+		if upSQL == "" {
+			upSQL = migration.SQL
+		}
+
+		statements = []string{upSQL}
+		if m.opts.MultiStatementEnabled {
+			split, err := splitSQLStatements(upSQL, m.opts.MultiStatementMaxSize)
+			if err != nil {
+				return fmt.Errorf("error splitting migration %d: %w", migration.Version, err)
+			}
+			statements = split
+		}
+	}
+
 	return m.client.ExecTx(ctx, func(tx pgx.Tx) error {
-		_, err := tx.Exec(ctx, migration.SQL)
-		if err != nil {
-			return fmt.Errorf("error applying migration %d: %w", migration.Version, err)
+		if migration.GoUp != nil {
+			if err := migration.GoUp(ctx, tx); err != nil {
+				return fmt.Errorf("error applying migration %d: %w", migration.Version, err)
+			}
+		} else {
+			for _, statement := range statements {
+				if err := m.execStatement(ctx, tx, statement); err != nil {
+					return fmt.Errorf("error applying migration %d: %w", migration.Version, err)
+				}
+			}
 		}
 
-		_, err = tx.Exec(ctx, `
-			INSERT INTO migrations (version, description, applied_at)
+		_, err := tx.Exec(ctx, fmt.Sprintf(`
+			INSERT INTO %s (version, description, applied_at)
 			VALUES ($1, $2, $3);
-		`, migration.Version, migration.Description, time.Now())
+		`, m.opts.MigrationsTable), migration.Version, migration.Description, time.Now())
 		if err != nil {
 			return fmt.Errorf("error registering migration %d: %w", migration.Version, err)
 		}
@@ -94,17 +190,50 @@ func (m *MigrationManager) ApplyMigration(ctx context.Context, migration Migrati
 	})
 }
 
-func (m *MigrationManager) Migrate(ctx context.Context, migrations []Migration) error {
+// execStatement runs a single statement of a migration inside tx, applying
+// the configured statement_timeout if one was set.
+func (m *MigrationManager) execStatement(ctx context.Context, tx pgx.Tx, statement string) error {
+	statement = strings.TrimSpace(statement)
+	if statement == "" {
+		return nil
+	}
+
+	if m.opts.StatementTimeoutMs > 0 {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", m.opts.StatementTimeoutMs)); err != nil {
+			return fmt.Errorf("error setting statement_timeout: %w", err)
+		}
+	}
+
+	_, err := tx.Exec(ctx, statement)
+	return err
+}
+
+// LatestMigrationVersion can be passed as targetVersion to Migrate to apply
+// every migration in the supplied list, regardless of its version number.
+const LatestMigrationVersion = -1
+
+// Migrate applies pending migrations from source, in ascending version
+// order, up to and including targetVersion. Pass LatestMigrationVersion to
+// apply everything.
+func (m *MigrationManager) Migrate(ctx context.Context, source MigrationSource, targetVersion int) error {
 	if err := m.InitMigrationTable(ctx); err != nil {
 		return err
 	}
 
+	migrations, err := source.List()
+	if err != nil {
+		return fmt.Errorf("error listing migrations: %w", err)
+	}
+
 	applied, err := m.GetAppliedMigrations(ctx)
 	if err != nil {
 		return err
 	}
 
-	for _, migration := range migrations {
+	for _, migration := range sortedByVersion(migrations) {
+		if targetVersion != LatestMigrationVersion && migration.Version > targetVersion {
+			break
+		}
 		if _, exists := applied[migration.Version]; exists {
 			continue
 		}
@@ -119,16 +248,137 @@ func (m *MigrationManager) Migrate(ctx context.Context, migrations []Migration)
 	return nil
 }
 
+// Rollback reverts applied migrations from source, in descending version
+// order, down to and including the one after targetVersion. Pass 0 to roll
+// back everything. Each migration rolled back must carry a DownSQL or GoDown.
+func (m *MigrationManager) Rollback(ctx context.Context, source MigrationSource, targetVersion int) error {
+	if err := m.InitMigrationTable(ctx); err != nil {
+		return err
+	}
+
+	migrations, err := source.List()
+	if err != nil {
+		return fmt.Errorf("error listing migrations: %w", err)
+	}
+
+	applied, err := m.GetAppliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	sorted := sortedByVersion(migrations)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		migration := sorted[i]
+		if migration.Version <= targetVersion {
+			break
+		}
+		if _, exists := applied[migration.Version]; !exists {
+			continue
+		}
+
+		if err := m.rollbackMigration(ctx, migration); err != nil {
+			return err
+		}
+
+		fmt.Printf("Rolled back migration %d: %s\n", migration.Version, migration.Description)
+	}
+
+	return nil
+}
+
+func (m *MigrationManager) rollbackMigration(ctx context.Context, migration Migration) error {
+	var statements []string
+	if migration.GoDown == nil {
+		if migration.DownSQL == "" {
+			return fmt.Errorf("migration %d (%s) has no down migration defined", migration.Version, migration.Description)
+		}
+
+		statements = []string{migration.DownSQL}
+		if m.opts.MultiStatementEnabled {
+			split, err := splitSQLStatements(migration.DownSQL, m.opts.MultiStatementMaxSize)
+			if err != nil {
+				return fmt.Errorf("error splitting rollback for migration %d: %w", migration.Version, err)
+			}
+			statements = split
+		}
+	}
+
+	return m.client.ExecTx(ctx, func(tx pgx.Tx) error {
+		if migration.GoDown != nil {
+			if err := migration.GoDown(ctx, tx); err != nil {
+				return fmt.Errorf("error rolling back migration %d: %w", migration.Version, err)
+			}
+		} else {
+			for _, statement := range statements {
+				if err := m.execStatement(ctx, tx, statement); err != nil {
+					return fmt.Errorf("error rolling back migration %d: %w", migration.Version, err)
+				}
+			}
+		}
+
+		_, err := tx.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE version = $1`, m.opts.MigrationsTable), migration.Version)
+		if err != nil {
+			return fmt.Errorf("error deleting migration record %d: %w", migration.Version, err)
+		}
+
+		return nil
+	})
+}
+
+// Status reports the applied/pending/dirty state of every migration in
+// source, so callers can build CLI equivalents of `migrate status`.
+func (m *MigrationManager) Status(ctx context.Context, source MigrationSource) ([]MigrationStatus, error) {
+	if err := m.InitMigrationTable(ctx); err != nil {
+		return nil, err
+	}
+
+	migrations, err := source.List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing migrations: %w", err)
+	}
+
+	applied, err := m.GetAppliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range sortedByVersion(migrations) {
+		status := MigrationStatus{
+			Version:     migration.Version,
+			Description: migration.Description,
+		}
+
+		if appliedAt, exists := applied[migration.Version]; exists {
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func sortedByVersion(migrations []Migration) []Migration {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
 func (m *MigrationManager) RollbackLastMigration(ctx context.Context, rollbacks map[int]string) error {
 	var lastVersion int
 	var lastAppliedAt time.Time
 
-	err := m.client.QueryRow(ctx, `
+	err := m.client.QueryRow(ctx, fmt.Sprintf(`
 		SELECT version, applied_at
-		FROM migrations
+		FROM %s
 		ORDER BY version DESC
 		LIMIT 1
-	`).Scan(&lastVersion, &lastAppliedAt)
+	`, m.opts.MigrationsTable)).Scan(&lastVersion, &lastAppliedAt)
 
 	if err != nil {
 		return fmt.Errorf("error getting last migration: %w", err)
@@ -145,10 +395,10 @@ func (m *MigrationManager) RollbackLastMigration(ctx context.Context, rollbacks
 			return fmt.Errorf("error applying rollback %d: %w", lastVersion, err)
 		}
 
-		_, err = tx.Exec(ctx, `
-			DELETE FROM migrations
+		_, err = tx.Exec(ctx, fmt.Sprintf(`
+			DELETE FROM %s
 			WHERE version = $1
-		`, lastVersion)
+		`, m.opts.MigrationsTable), lastVersion)
 		if err != nil {
 			return fmt.Errorf("error deleting migration record %d: %w", lastVersion, err)
 		}
@@ -157,49 +407,21 @@ func (m *MigrationManager) RollbackLastMigration(ctx context.Context, rollbacks
 	})
 }
 
-// LoadMigrationsFromPath loads migrations from SQL files in the specified directory path.
-// The function expects SQL files to be named with the pattern: {version}_{description}.sql
-// where version is a numeric identifier and description is the migration description.
-// example: 001_create_users.sql
+// LoadMigrationsFromPath loads migrations from SQL files in the specified
+// directory path. It is equivalent to DirSource{Path: migrationsPath}.List()
+// and is kept as a standalone function for backward compatibility.
+//
+// Two naming conventions are supported:
+//
+//   - Paired up/down files, à la golang-migrate: {version}_{description}.up.sql
+//     and {version}_{description}.down.sql. These are merged into a single
+//     Migration with both UpSQL and DownSQL set, so it can be used with
+//     both Migrate and Rollback.
+//   - A single file per version: {version}_{description}.sql. Its content
+//     populates SQL/UpSQL; DownSQL is left empty, so such a migration can be
+//     applied with Migrate but not reverted with Rollback.
+//
+// example: 001_create_users.up.sql, 001_create_users.down.sql
 func LoadMigrationsFromPath(migrationsPath string) ([]Migration, error) {
-	entries, err := os.ReadDir(migrationsPath)
-	if err != nil {
-		return nil, fmt.Errorf("error reading migrations directory: %w", err)
-	}
-
-	var migrations []Migration
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
-			parts := strings.SplitN(entry.Name(), "_", 2)
-			if len(parts) < 2 {
-				continue
-			}
-
-			version, err := strconv.Atoi(parts[0])
-			if err != nil {
-				continue
-			}
-
-			content, err := os.ReadFile(filepath.Join(migrationsPath, entry.Name()))
-			if err != nil {
-				return nil, fmt.Errorf("error reading migration file %s: %w", entry.Name(), err)
-			}
-
-			description := strings.TrimSuffix(entry.Name(), ".sql")
-
-			migration := Migration{
-				Version:     version,
-				Description: description,
-				SQL:         string(content),
-				Timestamp:   time.Now(),
-			}
-			migrations = append(migrations, migration)
-		}
-	}
-
-	sort.Slice(migrations, func(i, j int) bool {
-		return migrations[i].Version < migrations[j].Version
-	})
-
-	return migrations, nil
+	return DirSource{Path: migrationsPath}.List()
 }
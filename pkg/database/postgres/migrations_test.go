@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMigrationsFromPath(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"001_create_users.up.sql":   "CREATE TABLE users (id INT);",
+		"001_create_users.down.sql": "DROP TABLE users;",
+		"002_legacy.sql":            "CREATE TABLE legacy (id INT);",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("error writing fixture %s: %v", name, err)
+		}
+	}
+
+	migrations, err := LoadMigrationsFromPath(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(migrations) != 2 {
+		t.Fatalf("got %d migrations, want 2", len(migrations))
+	}
+
+	paired := migrations[0]
+	if paired.Version != 1 {
+		t.Fatalf("got version %d, want 1", paired.Version)
+	}
+	if paired.UpSQL != files["001_create_users.up.sql"] {
+		t.Errorf("UpSQL = %q, want %q", paired.UpSQL, files["001_create_users.up.sql"])
+	}
+	if paired.DownSQL != files["001_create_users.down.sql"] {
+		t.Errorf("DownSQL = %q, want %q", paired.DownSQL, files["001_create_users.down.sql"])
+	}
+
+	legacy := migrations[1]
+	if legacy.Version != 2 {
+		t.Fatalf("got version %d, want 2", legacy.Version)
+	}
+	if legacy.UpSQL != files["002_legacy.sql"] {
+		t.Errorf("UpSQL = %q, want %q", legacy.UpSQL, files["002_legacy.sql"])
+	}
+	if legacy.DownSQL != "" {
+		t.Errorf("DownSQL = %q, want empty", legacy.DownSQL)
+	}
+}
@@ -8,6 +8,96 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// CopyFrom bulk-inserts rows into table using Postgres' COPY protocol, which
+// is orders of magnitude faster than issuing batched INSERTs for ETL-sized
+// workloads. It returns the number of rows copied.
+func (c *Client) CopyFrom(ctx context.Context, table string, columns []string, rows pgx.CopyFromSource) (int64, error) {
+	c.logger.DebugMsg("Starting CopyFrom into %s (%d columns)", table, len(columns))
+
+	n, err := c.pool.CopyFrom(ctx, pgx.Identifier{table}, columns, rows)
+	if err != nil {
+		c.logger.Error().WithError(err).Msg("Error executing CopyFrom")
+		return n, fmt.Errorf("error copying into %s: %w", table, err)
+	}
+
+	c.logger.DebugMsg("CopyFrom into %s completed, rows copied: %d", table, n)
+	return n, nil
+}
+
+// CopyFromSlice adapts an in-memory slice into a pgx.CopyFromSource using
+// toRow to map each element to the row values expected by CopyFrom, so
+// callers don't have to hand-write a CopyFromSource implementation.
+func CopyFromSlice[T any](rows []T, toRow func(T) ([]any, error)) pgx.CopyFromSource {
+	return &sliceCopyFromSource[T]{rows: rows, toRow: toRow, index: -1}
+}
+
+type sliceCopyFromSource[T any] struct {
+	rows  []T
+	toRow func(T) ([]any, error)
+	index int
+}
+
+func (s *sliceCopyFromSource[T]) Next() bool {
+	s.index++
+	return s.index < len(s.rows)
+}
+
+func (s *sliceCopyFromSource[T]) Values() ([]any, error) {
+	return s.toRow(s.rows[s.index])
+}
+
+func (s *sliceCopyFromSource[T]) Err() error {
+	return nil
+}
+
+// QueryStream runs query and invokes fn once with the resulting pgx.Rows,
+// guaranteeing rows.Close() is called on every return path once the query
+// has started, including when fn returns an error or panics. If the query
+// itself fails, fn is not called and the error is returned.
+func (c *Client) QueryStream(ctx context.Context, query string, args []any, fn func(pgx.Rows) error) error {
+	c.logger.DebugMsg("Executing QueryStream %s with args %v", query, args)
+
+	rows, err := c.pool.Query(ctx, query, args...)
+	if err != nil {
+		c.logger.Error().WithError(err).Msg("Error executing QueryStream")
+		return fmt.Errorf("error querying: %w", err)
+	}
+	defer rows.Close()
+
+	if err := fn(rows); err != nil {
+		return err
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return nil
+}
+
+// CollectRows runs query and scans every row with scan, returning the
+// collected results. It saves callers from hand-writing the
+// Next/Scan/Err loop that otherwise shows up anywhere rows are read.
+func CollectRows[T any](ctx context.Context, c *Client, query string, args []any, scan func(pgx.Row) (T, error)) ([]T, error) {
+	var results []T
+
+	err := c.QueryStream(ctx, query, args, func(rows pgx.Rows) error {
+		for rows.Next() {
+			value, err := scan(rows)
+			if err != nil {
+				return fmt.Errorf("error scanning row: %w", err)
+			}
+			results = append(results, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
 func (c *Client) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
 	c.logger.DebugMsg("Executing QueryRow %s with args %v", query, args)
 	return c.pool.QueryRow(ctx, query, args...)
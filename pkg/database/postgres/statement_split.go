@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitSQLStatements splits sql into individual statements on top-level
+// semicolons. It understands $$...$$ (and $tag$...$tag$) dollar-quoted
+// bodies so semicolons inside PL/pgSQL function definitions aren't mistaken
+// for statement terminators, as well as single/double-quoted strings and
+// --/* */ comments. maxSize bounds the size in bytes of any single
+// statement; a value of 0 disables the check.
+func splitSQLStatements(sql string, maxSize int) ([]string, error) {
+	var statements []string
+	var current strings.Builder
+
+	var (
+		inSingleQuote  bool
+		inDoubleQuote  bool
+		inLineComment  bool
+		inBlockComment bool
+		dollarTag      string
+	)
+
+	flush := func() error {
+		statement := strings.TrimSpace(current.String())
+		current.Reset()
+		if statement == "" {
+			return nil
+		}
+		if maxSize > 0 && len(statement) > maxSize {
+			return fmt.Errorf("statement exceeds max size of %d bytes", maxSize)
+		}
+		statements = append(statements, statement)
+		return nil
+	}
+
+	n := len(sql)
+	for i := 0; i < n; {
+		ch := sql[i]
+
+		switch {
+		case inLineComment:
+			current.WriteByte(ch)
+			if ch == '\n' {
+				inLineComment = false
+			}
+			i++
+
+		case inBlockComment:
+			current.WriteByte(ch)
+			if ch == '*' && i+1 < n && sql[i+1] == '/' {
+				current.WriteByte('/')
+				i += 2
+				inBlockComment = false
+				continue
+			}
+			i++
+
+		case dollarTag != "":
+			if strings.HasPrefix(sql[i:], dollarTag) {
+				current.WriteString(dollarTag)
+				i += len(dollarTag)
+				dollarTag = ""
+				continue
+			}
+			current.WriteByte(ch)
+			i++
+
+		case inSingleQuote:
+			current.WriteByte(ch)
+			if ch == '\'' {
+				if i+1 < n && sql[i+1] == '\'' {
+					current.WriteByte(sql[i+1])
+					i += 2
+					continue
+				}
+				inSingleQuote = false
+			}
+			i++
+
+		case inDoubleQuote:
+			current.WriteByte(ch)
+			if ch == '"' {
+				inDoubleQuote = false
+			}
+			i++
+
+		case ch == '-' && i+1 < n && sql[i+1] == '-':
+			inLineComment = true
+			current.WriteByte(ch)
+			i++
+
+		case ch == '/' && i+1 < n && sql[i+1] == '*':
+			inBlockComment = true
+			current.WriteByte(ch)
+			i++
+
+		case ch == '\'':
+			inSingleQuote = true
+			current.WriteByte(ch)
+			i++
+
+		case ch == '"':
+			inDoubleQuote = true
+			current.WriteByte(ch)
+			i++
+
+		case ch == '$':
+			if tag, ok := readDollarTag(sql[i:]); ok {
+				dollarTag = tag
+				current.WriteString(tag)
+				i += len(tag)
+				continue
+			}
+			current.WriteByte(ch)
+			i++
+
+		case ch == ';':
+			current.WriteByte(ch)
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			i++
+
+		default:
+			current.WriteByte(ch)
+			i++
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+// readDollarTag reports whether s begins with a dollar-quote delimiter such
+// as "$$" or "$tag$", returning the full delimiter including both dollar
+// signs.
+func readDollarTag(s string) (string, bool) {
+	if len(s) == 0 || s[0] != '$' {
+		return "", false
+	}
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '$':
+			return s[:i+1], true
+		case s[i] == '_' || (s[i] >= 'a' && s[i] <= 'z') || (s[i] >= 'A' && s[i] <= 'Z') || (s[i] >= '0' && s[i] <= '9'):
+			continue
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
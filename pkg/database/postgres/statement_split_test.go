@@ -0,0 +1,76 @@
+package postgres
+
+import "testing"
+
+func TestSplitSQLStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "simple statements",
+			sql:  "CREATE TABLE a (id INT); CREATE TABLE b (id INT);",
+			want: []string{"CREATE TABLE a (id INT);", "CREATE TABLE b (id INT);"},
+		},
+		{
+			name: "semicolon inside string literal",
+			sql:  "INSERT INTO a (name) VALUES ('a; b'); SELECT 1;",
+			want: []string{"INSERT INTO a (name) VALUES ('a; b');", "SELECT 1;"},
+		},
+		{
+			name: "semicolon inside dollar-quoted function body",
+			sql: `CREATE FUNCTION f() RETURNS trigger AS $$
+BEGIN
+	NEW.updated_at := now();
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+SELECT 1;`,
+			want: []string{
+				"CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n\tNEW.updated_at := now();\n\tRETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;",
+				"SELECT 1;",
+			},
+		},
+		{
+			name: "semicolon inside line comment",
+			sql:  "-- drop the ; old table\nSELECT 1;",
+			want: []string{"-- drop the ; old table\nSELECT 1;"},
+		},
+		{
+			name:    "statement exceeds max size",
+			sql:     "SELECT 1; SELECT 2;",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxSize := 0
+			if tt.wantErr {
+				maxSize = 5
+			}
+
+			got, err := splitSQLStatements(tt.sql, maxSize)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d statements, want %d: %v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("statement %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// WaitOptions configures Wait's retry/backoff behavior.
+type WaitOptions struct {
+	// InitialDelay is how long to wait before the second attempt. Defaults
+	// to 250ms.
+	InitialDelay time.Duration
+	// MaxDelay caps the backoff delay between attempts. Defaults to 10s.
+	MaxDelay time.Duration
+	// MaxAttempts caps the number of connection attempts. 0 means retry
+	// until ctx is cancelled.
+	MaxAttempts int
+	// Jitter adds up to this fraction of the current delay as random noise,
+	// to avoid a thundering herd of clients retrying in lockstep. Defaults
+	// to 0.2 (20%).
+	Jitter float64
+}
+
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.InitialDelay == 0 {
+		o.InitialDelay = 250 * time.Millisecond
+	}
+	if o.MaxDelay == 0 {
+		o.MaxDelay = 10 * time.Second
+	}
+	if o.Jitter == 0 {
+		o.Jitter = 0.2
+	}
+	return o
+}
+
+// Wait repeatedly attempts Connect and Ping, backing off exponentially
+// between attempts, until the server accepts connections or ctx is
+// cancelled. It addresses the common container-startup race where the
+// application boots before Postgres is ready to accept connections.
+//
+// An authentication error (SQLSTATE 28000 or 28P01, e.g. a rejected role
+// or password) is returned immediately without retrying, since waiting
+// longer won't fix it; any other error, including a missing database, is
+// treated as "not ready yet" and retried.
+func (c *Client) Wait(ctx context.Context, opts WaitOptions) error {
+	opts = opts.withDefaults()
+
+	delay := opts.InitialDelay
+	var lastErr error
+
+	for attempt := 1; opts.MaxAttempts == 0 || attempt <= opts.MaxAttempts; attempt++ {
+		err := c.Connect(ctx)
+		if err == nil {
+			err = c.Ping(ctx)
+		}
+		if err == nil {
+			c.logger.InfoMsg("PostgreSQL is ready after %d attempt(s)", attempt)
+			return nil
+		}
+
+		lastErr = err
+		c.logger.DebugMsg("Attempt %d to reach PostgreSQL failed: %v", attempt, err)
+
+		if isAuthError(err) {
+			return fmt.Errorf("authentication failed after %d attempt(s): %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("postgres not ready after %d attempt(s): %w", attempt, ctx.Err())
+		case <-time.After(withJitter(delay, opts.Jitter)):
+		}
+
+		delay *= 2
+		if delay > opts.MaxDelay {
+			delay = opts.MaxDelay
+		}
+	}
+
+	return fmt.Errorf("postgres not ready after %d attempt(s): %w", opts.MaxAttempts, lastErr)
+}
+
+// NewClientAndWait builds a Client for cfg and blocks, via Wait, until it can
+// connect or ctx is cancelled.
+func NewClientAndWait(ctx context.Context, cfg Config, opts WaitOptions) (*Client, error) {
+	client := NewClient(cfg)
+	if err := client.Wait(ctx, opts); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// isAuthError reports whether err looks like a Postgres authentication
+// failure rather than a transient "server not ready yet" error.
+func isAuthError(err error) bool {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		switch pgErr.SQLState() {
+		case "28000", "28P01": // invalid_authorization_specification, invalid_password
+			return true
+		}
+	}
+	return false
+}
+
+func withJitter(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	spread := float64(d) * jitter
+	return d + time.Duration(rand.Float64()*spread)
+}